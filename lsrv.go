@@ -7,33 +7,20 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
-	"html/template"
 	"log"
-	"net/http"
 	"os"
-	"regexp"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
 
 	"github.com/larryr/lsrv/lsrv"
+	"github.com/larryr/lsrv/lsrv/store"
 )
 
-type Page struct {
-	Title string
-	Body  []byte
-}
-
-var validPath *regexp.Regexp
-var templates *template.Template
-
-func init() {
-	setup()
-
-	templates = template.Must(template.ParseFiles("edit.html", "view.html"))
-	validPath = regexp.MustCompile("^/(edit|save|view)/([a-zA-Z0-9]+)$")
-
-}
-
 const (
 	addr    = "0.0.0.0"
 	secCert = "cert.pem"
@@ -41,136 +28,100 @@ const (
 )
 
 var (
-	notls   *bool   = flag.Bool("notls", false, "use unsecure http")
-	gencert *bool   = flag.Bool("gencert", false, "generate cert and key")
-	port    *int    = flag.Int("port", 8080, "port for server to listen on")
-	host    *string = flag.String("host", "localhost", "host name for certificate")
+	notls   = flag.Bool("notls", false, "use unsecure http")
+	gencert = flag.Bool("gencert", false, "generate cert and key")
+	port    = flag.Int("port", 8080, "port for server to listen on")
+	host    = flag.String("host", "localhost", "host name for certificate")
+
+	httpPort        = flag.Int("http-port", 80, "port for the http->https redirect listener (only used when TLS is enabled)")
+	shutdownTimeout = flag.Duration("shutdown-timeout", 30*time.Second, "grace period to let in-flight requests finish on SIGINT/SIGTERM")
+
+	rateLimit     = flag.Int("rate-limit", 60, "requests allowed per client IP per -rate-window")
+	rateWindow    = flag.Duration("rate-window", time.Minute, "window over which -rate-limit and -save-rate-limit are enforced")
+	saveRateLimit = flag.Int("save-rate-limit", 10, "stricter requests-per-window limit applied to /save/")
+
+	dataDir   = flag.String("data-dir", ".", "directory the page store keeps its data in (fs page files, or lsrv.db for sqlite)")
+	storeKind = flag.String("store", "fs", "page storage backend: fs or sqlite")
+
+	authMode     = flag.String("auth-mode", "none", "require basic auth for: none|edit|all")
+	htpasswdPath = flag.String("htpasswd", "", "bcrypt htpasswd file for edit/save credentials")
+	netrcPath    = flag.String("netrc", defaultNetrcPath(), `fallback .netrc file seeding a single admin account (machine "lsrv"), used when -htpasswd is unset`)
+
+	render = flag.String("render", "markdown", "how to render /view/ page bodies: markdown or plain")
 )
 
+// defaultNetrcPath returns $HOME/.netrc, or "" if the home directory
+// cannot be determined.
+func defaultNetrcPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".netrc")
+}
+
 func main() {
 	flag.Parse()
 
-	var srvAddr = fmt.Sprintf("%s:%d", addr, *port)
-	fmt.Printf("lsrv: listening: %s\n", srvAddr)
-	log.Printf("args: notls=%v gencert=%v port=%v host=%v", *notls, *gencert, *port, *host)
-
 	if *gencert {
 		// generate certificate/key and exit.
-		err := lsrv.GenerateCert(*host, "")
-		if err != nil {
+		if err := lsrv.GenerateCert(*host, ""); err != nil {
 			log.Fatalf("error generating cert:%v", err)
 		}
 		log.Printf("certificate/key generated!\n")
 		return
 	}
 
-	// setup to run server
-	http.HandleFunc("/view/", makeHandler(viewHandler))
-	http.HandleFunc("/edit/", makeHandler(editHandler))
-	http.HandleFunc("/save/", makeHandler(saveHandler))
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		http.ServeFile(w, r, "content/"+r.URL.Path[1:])
-	})
-
-	var err error
-	if *notls {
-		err = http.ListenAndServe(srvAddr, nil)
-	} else {
-		err = http.ListenAndServeTLS(srvAddr, secCert, secKey, nil)
-	}
+	ps, err := newPageStore(*storeKind, *dataDir)
 	if err != nil {
-		log.Fatalf("error: %v", err)
+		log.Fatalf("error opening %s store: %v", *storeKind, err)
 	}
-	log.Printf("exiting!\n")
-}
 
-func (p *Page) save() error {
-	filename := p.Title + ".txt"
-	return os.WriteFile(filename, p.Body, 0600)
-}
-
-func loadPage(title string) (*Page, error) {
-	filename := title + ".txt"
-	body, err := os.ReadFile(filename)
-	if err != nil {
-		return nil, err
+	cfg := lsrv.Config{
+		Addr:            fmt.Sprintf("%s:%d", addr, *port),
+		ShutdownTimeout: *shutdownTimeout,
+		Store:           ps,
+		RateLimit:       *rateLimit,
+		RateWindow:      *rateWindow,
+		SaveRateLimit:   *saveRateLimit,
+		AuthMode:        *authMode,
+		HtpasswdPath:    *htpasswdPath,
+		NetrcPath:       *netrcPath,
+		Render:          *render,
 	}
-	return &Page{Title: title, Body: body}, nil
-}
-
-func viewHandler(w http.ResponseWriter, r *http.Request, title string) {
-	p, err := loadPage(title)
-	if err != nil {
-		http.Redirect(w, r, "/edit/"+title, http.StatusFound)
-		return
+	if !*notls {
+		cfg.TLSCertFile = secCert
+		cfg.TLSKeyFile = secKey
+		cfg.PublicHTTPSAddr = fmt.Sprintf("%s:%d", *host, *port)
+		cfg.HTTPPort = *httpPort
 	}
-	renderTemplate(w, "view", p)
-}
 
-func editHandler(w http.ResponseWriter, r *http.Request, title string) {
-	p, err := loadPage(title)
+	srv, err := lsrv.NewServer(cfg)
 	if err != nil {
-		p = &Page{Title: title}
+		log.Fatalf("error creating server: %v", err)
 	}
-	renderTemplate(w, "edit", p)
-}
 
-func saveHandler(w http.ResponseWriter, r *http.Request, title string) {
-	body := r.FormValue("body")
-	p := &Page{Title: title, Body: []byte(body)}
-	err := p.save()
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	http.Redirect(w, r, "/view/"+title, http.StatusFound)
-}
+	fmt.Printf("lsrv: listening: %s\n", cfg.Addr)
+	log.Printf("args: notls=%v gencert=%v port=%v host=%v", *notls, *gencert, *port, *host)
 
-func renderTemplate(w http.ResponseWriter, tmpl string, p *Page) {
-	err := templates.ExecuteTemplate(w, tmpl+".html", p)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-	}
-}
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-func makeHandler(fn func(http.ResponseWriter, *http.Request, string)) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		m := validPath.FindStringSubmatch(r.URL.Path)
-		if m == nil {
-			http.NotFound(w, r)
-			return
-		}
-		fn(w, r, m[2])
+	if err := srv.ListenAndServe(ctx); err != nil {
+		log.Fatalf("error: %v", err)
 	}
+	log.Printf("exiting!\n")
 }
 
-var (
-	edit_html string = `
-<h1>Editing {{.Title}}</h1>
-
-<form action="/save/{{.Title}}" method="POST">
-<div><textarea name="body" rows="20" cols="80">{{printf "%s" .Body}}</textarea></div>
-<div><input type="submit" value="Save"></div>
-</form>`
-
-	view_html string = `
-	<h1>{{.Title}}</h1>
-<p>[<a href="/edit/{{.Title}}">edit</a>]</p>
-<div>{{printf "%s" .Body}}</div>
-`
-)
-
-func setup() {
-
-	makeFile("edit.html", edit_html)
-
-	makeFile("view.html", view_html)
-
-}
-
-func makeFile(name, content string) {
-	out, err := os.Create(name)
-	if err == nil {
-		out.WriteString(content)
+// newPageStore builds the PageStore selected by -store, rooted/located
+// per -data-dir.
+func newPageStore(kind, dataDir string) (store.PageStore, error) {
+	switch kind {
+	case "fs":
+		return store.NewFSStore(dataDir)
+	case "sqlite":
+		return store.NewSQLiteStore(filepath.Join(dataDir, "lsrv.db"))
+	default:
+		return nil, fmt.Errorf("unknown -store %q (want fs or sqlite)", kind)
 	}
 }
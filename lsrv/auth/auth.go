@@ -0,0 +1,12 @@
+// Copyright 2022 Larry Rau. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package auth provides credential sources for gating lsrv's edit/save
+// endpoints behind HTTP Basic authentication.
+package auth
+
+// Checker verifies a username/password pair presented via Basic auth.
+type Checker interface {
+	Verify(user, password string) bool
+}
@@ -0,0 +1,53 @@
+// Copyright 2022 Larry Rau. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Htpasswd holds bcrypt-hashed credentials parsed from an htpasswd file,
+// keyed by username.
+type Htpasswd map[string]string
+
+// LoadHtpasswd parses "user:bcrypthash" lines out of the file at path.
+// Blank lines and lines starting with "#" are ignored.
+func LoadHtpasswd(path string) (Htpasswd, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	creds := make(Htpasswd)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		user, hash, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("auth: malformed htpasswd line %q", line)
+		}
+		creds[user] = hash
+	}
+	return creds, scanner.Err()
+}
+
+// Verify reports whether password matches the bcrypt hash on file for
+// user.
+func (h Htpasswd) Verify(user, password string) bool {
+	hash, ok := h[user]
+	if !ok {
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
@@ -0,0 +1,56 @@
+// Copyright 2022 Larry Rau. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// bcryptHash is bcrypt("secret") at cost 10, pre-computed so tests don't
+// pay bcrypt's deliberately slow hashing cost on every run.
+const bcryptHash = "$2a$10$.p7auat3elySk4.4dPhYi.b8mi8VGmL9HL7HKIzwCi778CX0HDIRG"
+
+func writeHtpasswd(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "htpasswd")
+	if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatalf("write htpasswd: %v", err)
+	}
+	return path
+}
+
+func TestLoadHtpasswdSkipsBlankAndCommentLines(t *testing.T) {
+	path := writeHtpasswd(t, "# a comment\n\nadmin:"+bcryptHash+"\n")
+	creds, err := LoadHtpasswd(path)
+	if err != nil {
+		t.Fatalf("LoadHtpasswd: %v", err)
+	}
+	if len(creds) != 1 || creds["admin"] != bcryptHash {
+		t.Errorf("creds = %v, want a single admin entry", creds)
+	}
+}
+
+func TestLoadHtpasswdMalformedLine(t *testing.T) {
+	path := writeHtpasswd(t, "not-a-valid-line\n")
+	if _, err := LoadHtpasswd(path); err == nil {
+		t.Errorf("LoadHtpasswd should reject a line with no ':'")
+	}
+}
+
+func TestHtpasswdVerify(t *testing.T) {
+	h := Htpasswd{"admin": bcryptHash}
+
+	if !h.Verify("admin", "secret") {
+		t.Errorf("Verify with the correct password should succeed")
+	}
+	if h.Verify("admin", "wrong") {
+		t.Errorf("Verify with the wrong password should fail")
+	}
+	if h.Verify("nobody", "secret") {
+		t.Errorf("Verify for an unknown user should fail")
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2022 Larry Rau. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+)
+
+// NetrcEntry is one "machine" stanza parsed from a .netrc file.
+type NetrcEntry struct {
+	Machine  string
+	Login    string
+	Password string
+}
+
+// ParseNetrc reads "machine host login user password pass" stanzas out
+// of the file at path, modeled on the minimal reader in Go's
+// cmd/go/internal/auth.
+func ParseNetrc(path string) ([]NetrcEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []NetrcEntry
+	var cur *NetrcEntry
+	var key string
+
+	scanner := bufio.NewScanner(f)
+	scanner.Split(bufio.ScanWords)
+	for scanner.Scan() {
+		tok := scanner.Text()
+		switch tok {
+		case "machine", "login", "password":
+			key = tok
+			if tok == "machine" {
+				entries = append(entries, NetrcEntry{})
+				cur = &entries[len(entries)-1]
+			}
+		default:
+			if cur == nil {
+				continue
+			}
+			switch key {
+			case "machine":
+				cur.Machine = tok
+			case "login":
+				cur.Login = tok
+			case "password":
+				cur.Password = tok
+			}
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// AdminChecker authenticates a single admin account, as seeded from a
+// .netrc entry when no htpasswd file is configured.
+type AdminChecker struct {
+	user, pass string
+}
+
+// AdminFromNetrc looks up the entry for machine in the .netrc file at
+// path and returns a Checker for that one account.
+func AdminFromNetrc(path, machine string) (*AdminChecker, error) {
+	entries, err := ParseNetrc(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.Machine == machine {
+			return &AdminChecker{user: e.Login, pass: e.Password}, nil
+		}
+	}
+	return nil, fmt.Errorf("auth: no %q entry in %s", machine, path)
+}
+
+// Verify reports whether user/password match the seeded admin account.
+func (a *AdminChecker) Verify(user, password string) bool {
+	return user == a.user && password == a.pass
+}
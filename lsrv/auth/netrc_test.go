@@ -0,0 +1,63 @@
+// Copyright 2022 Larry Rau. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeNetrc(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".netrc")
+	if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatalf("write netrc: %v", err)
+	}
+	return path
+}
+
+func TestParseNetrc(t *testing.T) {
+	path := writeNetrc(t, `
+machine wiki.example.com
+login admin
+password hunter2
+
+machine other.example.com
+login carol
+password swordfish
+`)
+
+	entries, err := ParseNetrc(path)
+	if err != nil {
+		t.Fatalf("ParseNetrc: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("ParseNetrc returned %d entries, want 2", len(entries))
+	}
+	want := NetrcEntry{Machine: "wiki.example.com", Login: "admin", Password: "hunter2"}
+	if entries[0] != want {
+		t.Errorf("entries[0] = %+v, want %+v", entries[0], want)
+	}
+}
+
+func TestAdminFromNetrc(t *testing.T) {
+	path := writeNetrc(t, "machine wiki.example.com\nlogin admin\npassword hunter2\n")
+
+	checker, err := AdminFromNetrc(path, "wiki.example.com")
+	if err != nil {
+		t.Fatalf("AdminFromNetrc: %v", err)
+	}
+	if !checker.Verify("admin", "hunter2") {
+		t.Errorf("Verify with the correct credentials should succeed")
+	}
+	if checker.Verify("admin", "wrong") {
+		t.Errorf("Verify with the wrong password should fail")
+	}
+
+	if _, err := AdminFromNetrc(path, "no-such-machine"); err == nil {
+		t.Errorf("AdminFromNetrc should error when the machine isn't present")
+	}
+}
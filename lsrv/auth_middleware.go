@@ -0,0 +1,44 @@
+// Copyright 2022 Larry Rau. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsrv
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/larryr/lsrv/lsrv/auth"
+)
+
+// netrcAdminMachine is the .netrc "machine" stanza used to seed the
+// single admin account when no Config.HtpasswdPath is set.
+const netrcAdminMachine = "lsrv"
+
+// resolveAuthChecker builds the credential source for Config.AuthMode,
+// preferring an htpasswd file and falling back to a .netrc-seeded admin
+// account.
+func resolveAuthChecker(htpasswdPath, netrcPath string) (auth.Checker, error) {
+	if htpasswdPath != "" {
+		return auth.LoadHtpasswd(htpasswdPath)
+	}
+	return auth.AdminFromNetrc(netrcPath, netrcAdminMachine)
+}
+
+// requireAuth gates next behind HTTP Basic auth, logging failed attempts
+// per client IP only while failLimiter allows them (further failures in
+// the same window are rejected without a log line).
+func requireAuth(checker auth.Checker, failLimiter *rateLimiter, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || !checker.Verify(user, pass) {
+			if allowed, _ := failLimiter.allow(clientIP(r)); allowed {
+				log.Printf("auth: failed login attempt user=%q from=%s", user, clientIP(r))
+			}
+			w.Header().Set("WWW-Authenticate", `Basic realm="lsrv"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
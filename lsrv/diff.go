@@ -0,0 +1,103 @@
+// Copyright 2022 Larry Rau. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsrv
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// unifiedDiff renders a line-level diff between a and b in unified-diff
+// notation ("---"/"+++" headers, then each line prefixed with ' ', '-' or
+// '+'), built from the longest-common-subsequence backtrace in diffLines.
+// Every line of both inputs is shown: there are no "@@" hunk headers and
+// unchanged lines are not elided.
+func unifiedDiff(titleA, titleB string, a, b []byte) string {
+	linesA := splitLines(a)
+	linesB := splitLines(b)
+	ops := diffLines(linesA, linesB)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "--- %s\n", titleA)
+	fmt.Fprintf(&buf, "+++ %s\n", titleB)
+	for _, op := range ops {
+		switch op.kind {
+		case diffEqual:
+			fmt.Fprintf(&buf, " %s\n", op.line)
+		case diffDelete:
+			fmt.Fprintf(&buf, "-%s\n", op.line)
+		case diffInsert:
+			fmt.Fprintf(&buf, "+%s\n", op.line)
+		}
+	}
+	return buf.String()
+}
+
+func splitLines(body []byte) []string {
+	if len(body) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(body), "\n"), "\n")
+}
+
+type diffKind int
+
+const (
+	diffEqual diffKind = iota
+	diffDelete
+	diffInsert
+)
+
+type diffOp struct {
+	kind diffKind
+	line string
+}
+
+// diffLines computes a line-level diff between a and b via the classic
+// dynamic-programming longest common subsequence, then backtracks it
+// into a sequence of equal/delete/insert operations.
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{diffEqual, a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{diffDelete, a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{diffInsert, b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, diffOp{diffDelete, a[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, diffOp{diffInsert, b[j]})
+	}
+	return ops
+}
@@ -0,0 +1,81 @@
+// Copyright 2022 Larry Rau. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsrv
+
+import (
+	"html/template"
+	"net/http"
+	"regexp"
+
+	"github.com/larryr/lsrv/lsrv/store"
+)
+
+// Page is an alias for store.Page.
+type Page = store.Page
+
+// viewPage is the data passed to view.html; Body is pre-rendered and
+// sanitized so the template can emit it unescaped.
+type viewPage struct {
+	Title string
+	Body  template.HTML
+}
+
+var validPath = regexp.MustCompile("^/(edit|save|view|history)/([a-zA-Z0-9]+)$")
+
+func (s *Server) viewHandler(w http.ResponseWriter, r *http.Request, title string) {
+	p, err := s.cfg.Store.Load(title)
+	if err != nil {
+		http.Redirect(w, r, "/edit/"+title, http.StatusFound)
+		return
+	}
+
+	var body template.HTML
+	if s.cfg.Render == "plain" {
+		body = renderPlain(p.Body)
+	} else {
+		body, err = renderHTML(p.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	s.renderTemplate(w, "view", viewPage{Title: p.Title, Body: body})
+}
+
+func (s *Server) editHandler(w http.ResponseWriter, r *http.Request, title string) {
+	p, err := s.cfg.Store.Load(title)
+	if err != nil {
+		p = &Page{Title: title}
+	}
+	s.renderTemplate(w, "edit", p)
+}
+
+func (s *Server) saveHandler(w http.ResponseWriter, r *http.Request, title string) {
+	body := r.FormValue("body")
+	p := &Page{Title: title, Body: []byte(body)}
+	if err := s.cfg.Store.Save(p); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/view/"+title, http.StatusFound)
+}
+
+func (s *Server) renderTemplate(w http.ResponseWriter, tmpl string, data any) {
+	if err := s.templates.ExecuteTemplate(w, tmpl+".html", data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// makeHandler adapts a /{action}/{title} handler to http.HandlerFunc.
+func makeHandler(fn func(http.ResponseWriter, *http.Request, string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		m := validPath.FindStringSubmatch(r.URL.Path)
+		if m == nil {
+			http.NotFound(w, r)
+			return
+		}
+		fn(w, r, m[2])
+	}
+}
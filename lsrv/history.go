@@ -0,0 +1,113 @@
+// Copyright 2022 Larry Rau. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsrv
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/larryr/lsrv/lsrv/store"
+)
+
+var diffPath = regexp.MustCompile("^/diff/([a-zA-Z0-9]+)/([a-zA-Z0-9]+)/([a-zA-Z0-9]+)$")
+var revertPath = regexp.MustCompile("^/revert/([a-zA-Z0-9]+)/([a-zA-Z0-9]+)$")
+
+// historyView is the data passed to history.html.
+type historyView struct {
+	Title     string
+	Revisions []historyRow
+}
+
+// historyRow is one revision as listed on the history page, plus the ID
+// of the next-older revision (empty for the oldest) so the template can
+// link "diff vs previous".
+type historyRow struct {
+	store.Revision
+	PrevID string
+}
+
+// diffView is the data passed to diff.html.
+type diffView struct {
+	Title string
+	RevA  string
+	RevB  string
+	Diff  string
+}
+
+func (s *Server) historyHandler(w http.ResponseWriter, r *http.Request, title string) {
+	revs, err := s.cfg.Store.History(title)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	rows := make([]historyRow, len(revs))
+	for i, rev := range revs {
+		rows[i] = historyRow{Revision: rev}
+		if i+1 < len(revs) {
+			rows[i].PrevID = revs[i+1].ID
+		}
+	}
+	s.renderTemplate(w, "history", historyView{Title: title, Revisions: rows})
+}
+
+func (s *Server) diffHandler(w http.ResponseWriter, r *http.Request, title, revA, revB string) {
+	a, err := s.cfg.Store.LoadRevision(title, revA)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	b, err := s.cfg.Store.LoadRevision(title, revB)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	d := unifiedDiff(title+"@"+revA, title+"@"+revB, a.Body, b.Body)
+	s.renderTemplate(w, "diff", diffView{Title: title, RevA: revA, RevB: revB, Diff: d})
+}
+
+// revertHandler writes a new revision equal to the content of revID,
+// making it the current one without losing the intervening history.
+func (s *Server) revertHandler(w http.ResponseWriter, r *http.Request, title, revID string) {
+	rev, err := s.cfg.Store.LoadRevision(title, revID)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	if err := s.cfg.Store.Save(&Page{Title: title, Body: rev.Body}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/view/"+title, http.StatusFound)
+}
+
+// makeDiffHandler adapts a /diff/{title}/{revA}/{revB} handler to
+// http.HandlerFunc, mirroring makeHandler for the three-segment route.
+func makeDiffHandler(fn func(http.ResponseWriter, *http.Request, string, string, string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		m := diffPath.FindStringSubmatch(r.URL.Path)
+		if m == nil {
+			http.NotFound(w, r)
+			return
+		}
+		fn(w, r, m[1], m[2], m[3])
+	}
+}
+
+// makeRevertHandler adapts a /revert/{title}/{revID} POST handler to
+// http.HandlerFunc, mirroring makeHandler for the two-segment route.
+func makeRevertHandler(fn func(http.ResponseWriter, *http.Request, string, string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		m := revertPath.FindStringSubmatch(r.URL.Path)
+		if m == nil {
+			http.NotFound(w, r)
+			return
+		}
+		fn(w, r, m[1], m[2])
+	}
+}
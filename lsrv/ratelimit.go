@@ -0,0 +1,102 @@
+// Copyright 2022 Larry Rau. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsrv
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// bucket is a fixed-window token bucket for a single client IP.
+type bucket struct {
+	tokens  int
+	resetAt time.Time
+}
+
+// rateLimiter is an in-memory, per-IP token bucket limiter. It is safe for
+// concurrent use and must have sweep run periodically to bound memory
+// growth from unique client IPs.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   int
+	window time.Duration
+	seen   map[string]*bucket
+}
+
+func newRateLimiter(rate int, window time.Duration) *rateLimiter {
+	return &rateLimiter{
+		rate:   rate,
+		window: window,
+		seen:   make(map[string]*bucket),
+	}
+}
+
+// allow reports whether key (typically a client IP) may proceed. When it
+// returns false, retryAfter is how long the caller should wait before
+// trying again.
+func (rl *rateLimiter) allow(key string) (ok bool, retryAfter time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	b, found := rl.seen[key]
+	if !found || now.After(b.resetAt) {
+		b = &bucket{tokens: rl.rate, resetAt: now.Add(rl.window)}
+		rl.seen[key] = b
+	}
+	if b.tokens <= 0 {
+		return false, b.resetAt.Sub(now)
+	}
+	b.tokens--
+	return true, 0
+}
+
+// sweep deletes expired buckets every window until stop is closed.
+func (rl *rateLimiter) sweep(stop <-chan struct{}) {
+	ticker := time.NewTicker(rl.window)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			rl.mu.Lock()
+			for key, b := range rl.seen {
+				if now.After(b.resetAt) {
+					delete(rl.seen, key)
+				}
+			}
+			rl.mu.Unlock()
+		}
+	}
+}
+
+// rateLimitMiddleware rejects requests from a client IP once it has
+// exhausted its bucket, responding 429 with a Retry-After header.
+func rateLimitMiddleware(rl *rateLimiter, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := clientIP(r)
+		ok, retryAfter := rl.allow(key)
+		if !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+			http.Error(w, "too many requests", http.StatusTooManyRequests)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// clientIP extracts the request's client IP, stripping the port from
+// RemoteAddr where present.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,87 @@
+// Copyright 2022 Larry Rau. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsrv
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	rl := newRateLimiter(2, time.Minute)
+
+	if ok, _ := rl.allow("1.2.3.4"); !ok {
+		t.Fatalf("1st request should be allowed")
+	}
+	if ok, _ := rl.allow("1.2.3.4"); !ok {
+		t.Fatalf("2nd request should be allowed")
+	}
+	ok, retryAfter := rl.allow("1.2.3.4")
+	if ok {
+		t.Fatalf("3rd request should be rejected")
+	}
+	if retryAfter <= 0 {
+		t.Errorf("retryAfter = %v, want > 0", retryAfter)
+	}
+
+	// A different key has its own bucket.
+	if ok, _ := rl.allow("5.6.7.8"); !ok {
+		t.Fatalf("request from a different IP should be allowed")
+	}
+}
+
+func TestRateLimiterSweepExpiresBuckets(t *testing.T) {
+	rl := newRateLimiter(1, 10*time.Millisecond)
+	if ok, _ := rl.allow("1.2.3.4"); !ok {
+		t.Fatalf("1st request should be allowed")
+	}
+	if ok, _ := rl.allow("1.2.3.4"); ok {
+		t.Fatalf("2nd request should be rejected before the window expires")
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go rl.sweep(stop)
+
+	time.Sleep(50 * time.Millisecond)
+
+	rl.mu.Lock()
+	_, found := rl.seen["1.2.3.4"]
+	rl.mu.Unlock()
+	if found {
+		t.Errorf("sweep should have deleted the expired bucket")
+	}
+
+	if ok, _ := rl.allow("1.2.3.4"); !ok {
+		t.Errorf("request after sweep should get a fresh bucket")
+	}
+}
+
+func TestRateLimitMiddlewareReturns429(t *testing.T) {
+	rl := newRateLimiter(1, time.Minute)
+	calls := 0
+	h := rateLimitMiddleware(rl, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/view/X", nil)
+	req.RemoteAddr = "9.9.9.9:1234"
+
+	h(httptest.NewRecorder(), req)
+
+	w := httptest.NewRecorder()
+	h(w, req)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("2nd request status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Errorf("expected a Retry-After header on 429")
+	}
+	if calls != 1 {
+		t.Errorf("handler called %d times, want 1", calls)
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2022 Larry Rau. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsrv
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+)
+
+// wikiWordPattern matches CamelCase WikiWords so they can be auto-linked
+// to the page they name, e.g. "FrontPage" -> "/view/FrontPage".
+var wikiWordPattern = regexp.MustCompile(`\b[A-Z][a-z0-9]+(?:[A-Z][a-z0-9]+)+\b`)
+
+var (
+	markdownRenderer = goldmark.New()
+	ugcPolicy        = bluemonday.UGCPolicy()
+)
+
+// rewriteWikiWords turns WikiWord occurrences in CommonMark source into
+// markdown links, before the source is handed to the markdown renderer.
+func rewriteWikiWords(body []byte) []byte {
+	return wikiWordPattern.ReplaceAllFunc(body, func(word []byte) []byte {
+		return []byte(fmt.Sprintf("[%s](/view/%s)", word, word))
+	})
+}
+
+// renderHTML converts body from CommonMark to sanitized HTML: WikiWords
+// are linked, the result is parsed as markdown, then passed through
+// bluemonday's UGC allow-list to strip scripts, event handlers, and
+// dangerous URLs before it reaches the template unescaped.
+func renderHTML(body []byte) (template.HTML, error) {
+	var buf bytes.Buffer
+	if err := markdownRenderer.Convert(rewriteWikiWords(body), &buf); err != nil {
+		return "", err
+	}
+	return template.HTML(ugcPolicy.SanitizeBytes(buf.Bytes())), nil
+}
+
+// renderPlain escapes body for literal display, bypassing markdown
+// rendering entirely (the -render=plain opt-out).
+func renderPlain(body []byte) template.HTML {
+	return template.HTML(template.HTMLEscapeString(string(body)))
+}
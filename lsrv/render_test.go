@@ -0,0 +1,44 @@
+// Copyright 2022 Larry Rau. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsrv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderHTMLSanitizesKnownPayloads(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    string // must NOT appear in the rendered output
+	}{
+		{"script tag", "<script>alert(1)</script>", "<script"},
+		{"img onerror", `<img src=x onerror="alert(1)">`, "onerror"},
+		{"javascript href", "[click me](javascript:alert(1))", "javascript:"},
+		{"inline event handler", `<div onclick="alert(1)">hi</div>`, "onclick"},
+		{"data url script", `<a href="data:text/html,<script>alert(1)</script>">x</a>`, "<script"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderHTML([]byte(tt.payload))
+			if err != nil {
+				t.Fatalf("renderHTML: %v", err)
+			}
+			if strings.Contains(string(got), tt.want) {
+				t.Errorf("renderHTML(%q) = %q, still contains %q", tt.payload, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRewriteWikiWords(t *testing.T) {
+	got := string(rewriteWikiWords([]byte("see FrontPage for details")))
+	want := "see [FrontPage](/view/FrontPage) for details"
+	if got != want {
+		t.Errorf("rewriteWikiWords = %q, want %q", got, want)
+	}
+}
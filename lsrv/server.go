@@ -0,0 +1,263 @@
+// Copyright 2022 Larry Rau. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsrv
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/larryr/lsrv/lsrv/auth"
+	"github.com/larryr/lsrv/lsrv/store"
+)
+
+// Config configures a Server.
+type Config struct {
+	// Addr is the listen address, e.g. "0.0.0.0:8080".
+	Addr string
+
+	// TLSCertFile/TLSKeyFile enable HTTPS when set. PublicHTTPSAddr is
+	// the externally-visible "host:port" used to build http->https
+	// redirect targets, and HTTPPort is the redirect listener's port.
+	TLSCertFile     string
+	TLSKeyFile      string
+	PublicHTTPSAddr string
+	HTTPPort        int
+
+	// ShutdownTimeout bounds how long ListenAndServe waits for
+	// in-flight requests to finish once its context is canceled.
+	ShutdownTimeout time.Duration
+
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+
+	// Store is required.
+	Store store.PageStore
+
+	RateLimit     int
+	RateWindow    time.Duration
+	SaveRateLimit int
+
+	// AuthMode is one of "none", "edit", or "all"; "edit" gates
+	// /edit/, /save/ and /revert/, "all" additionally gates /view/.
+	AuthMode     string
+	HtpasswdPath string
+	NetrcPath    string
+
+	// Render is "markdown" (default) or "plain" to opt out of
+	// CommonMark rendering on /view/.
+	Render string
+}
+
+func (c *Config) setDefaults() {
+	if c.RateLimit == 0 {
+		c.RateLimit = 60
+	}
+	if c.SaveRateLimit == 0 {
+		c.SaveRateLimit = 10
+	}
+	if c.RateWindow == 0 {
+		c.RateWindow = time.Minute
+	}
+	if c.ReadTimeout == 0 {
+		c.ReadTimeout = 10 * time.Second
+	}
+	if c.WriteTimeout == 0 {
+		c.WriteTimeout = 10 * time.Second
+	}
+	if c.IdleTimeout == 0 {
+		c.IdleTimeout = 60 * time.Second
+	}
+	if c.ShutdownTimeout == 0 {
+		c.ShutdownTimeout = 30 * time.Second
+	}
+	if c.AuthMode == "" {
+		c.AuthMode = "none"
+	}
+	if c.Render == "" {
+		c.Render = "markdown"
+	}
+}
+
+// Server is the lsrv wiki's HTTP surface: routing, handlers, rate
+// limiting, and auth, independent of how it is hosted. It can be
+// embedded into another binary or run as multiple instances in tests.
+type Server struct {
+	cfg       Config
+	mux       *http.ServeMux
+	templates *template.Template
+
+	limiter     *rateLimiter
+	saveLimiter *rateLimiter
+	failLimiter *rateLimiter
+}
+
+// NewServer builds a Server from cfg. cfg.Store is required.
+func NewServer(cfg Config) (*Server, error) {
+	if cfg.Store == nil {
+		return nil, fmt.Errorf("lsrv: Config.Store is required")
+	}
+	cfg.setDefaults()
+	switch cfg.AuthMode {
+	case "none", "edit", "all":
+	default:
+		return nil, fmt.Errorf("lsrv: Config.AuthMode must be one of none, edit, all, got %q", cfg.AuthMode)
+	}
+
+	tmpl, err := parseTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		cfg:         cfg,
+		mux:         http.NewServeMux(),
+		templates:   tmpl,
+		limiter:     newRateLimiter(cfg.RateLimit, cfg.RateWindow),
+		saveLimiter: newRateLimiter(cfg.SaveRateLimit, cfg.RateWindow),
+		failLimiter: newRateLimiter(5, time.Minute),
+	}
+
+	var checker auth.Checker
+	if cfg.AuthMode != "none" {
+		checker, err = resolveAuthChecker(cfg.HtpasswdPath, cfg.NetrcPath)
+		if err != nil {
+			return nil, fmt.Errorf("lsrv: loading credentials for -auth-mode=%s: %w", cfg.AuthMode, err)
+		}
+	}
+
+	s.routes(checker)
+	return s, nil
+}
+
+// Handler returns the Server's routed http.Handler, for embedding or for
+// tests via httptest.NewServer.
+func (s *Server) Handler() http.Handler { return s.mux }
+
+func (s *Server) routes(checker auth.Checker) {
+	viewH := makeHandler(s.viewHandler)
+	editH := makeHandler(s.editHandler)
+	saveH := makeHandler(s.saveHandler)
+	revertH := makeRevertHandler(s.revertHandler)
+	if s.cfg.AuthMode == "edit" || s.cfg.AuthMode == "all" {
+		editH = requireAuth(checker, s.failLimiter, editH)
+		saveH = requireAuth(checker, s.failLimiter, saveH)
+		revertH = requireAuth(checker, s.failLimiter, revertH)
+	}
+	if s.cfg.AuthMode == "all" {
+		viewH = requireAuth(checker, s.failLimiter, viewH)
+	}
+
+	s.mux.HandleFunc("/view/", rateLimitMiddleware(s.limiter, viewH))
+	s.mux.HandleFunc("/edit/", rateLimitMiddleware(s.limiter, editH))
+	s.mux.HandleFunc("/save/", rateLimitMiddleware(s.saveLimiter, saveH))
+	s.mux.HandleFunc("/history/", rateLimitMiddleware(s.limiter, makeHandler(s.historyHandler)))
+	s.mux.HandleFunc("/diff/", rateLimitMiddleware(s.limiter, makeDiffHandler(s.diffHandler)))
+	s.mux.HandleFunc("/revert/", rateLimitMiddleware(s.saveLimiter, revertH))
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "content/"+r.URL.Path[1:])
+	})
+}
+
+// ListenAndServe runs the Server until ctx is canceled, then drains
+// in-flight requests for cfg.ShutdownTimeout before returning. When
+// cfg.TLSCertFile is set it also runs an http->https redirect listener
+// on cfg.HTTPPort.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	sweepStop := make(chan struct{})
+	go s.limiter.sweep(sweepStop)
+	go s.saveLimiter.sweep(sweepStop)
+	go s.failLimiter.sweep(sweepStop)
+	defer close(sweepStop)
+
+	srv := &http.Server{
+		Addr:         s.cfg.Addr,
+		Handler:      s.mux,
+		ReadTimeout:  s.cfg.ReadTimeout,
+		WriteTimeout: s.cfg.WriteTimeout,
+		IdleTimeout:  s.cfg.IdleTimeout,
+	}
+
+	var redirectSrv *http.Server
+	if s.cfg.TLSCertFile != "" {
+		redirectSrv = s.newRedirectServer()
+	}
+
+	errc := make(chan error, 2)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		var err error
+		if s.cfg.TLSCertFile != "" {
+			err = srv.ListenAndServeTLS(s.cfg.TLSCertFile, s.cfg.TLSKeyFile)
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			errc <- err
+		}
+	}()
+	if redirectSrv != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := redirectSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				errc <- err
+			}
+		}()
+	}
+
+	select {
+	case <-ctx.Done():
+		log.Printf("lsrv: shutting down (grace period %v)\n", s.cfg.ShutdownTimeout)
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), s.cfg.ShutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("lsrv: error shutting down server: %v", err)
+		}
+		if redirectSrv != nil {
+			if err := redirectSrv.Shutdown(shutdownCtx); err != nil {
+				log.Printf("lsrv: error shutting down redirect server: %v", err)
+			}
+		}
+	case err := <-errc:
+		// One listener failed outright (bad cert, port in use, ...).
+		// Bring the sibling down too instead of leaking its socket and
+		// goroutine for the rest of the process lifetime.
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), s.cfg.ShutdownTimeout)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+		if redirectSrv != nil {
+			redirectSrv.Shutdown(shutdownCtx)
+		}
+		wg.Wait()
+		return err
+	}
+	wg.Wait()
+	return nil
+}
+
+// newRedirectServer returns an http.Server that 301-redirects every
+// request to the https equivalent served at cfg.PublicHTTPSAddr.
+func (s *Server) newRedirectServer() *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		target := fmt.Sprintf("https://%s%s", s.cfg.PublicHTTPSAddr, r.URL.RequestURI())
+		http.Redirect(w, r, target, http.StatusMovedPermanently)
+	})
+	return &http.Server{
+		Addr:         fmt.Sprintf(":%d", s.cfg.HTTPPort),
+		Handler:      mux,
+		ReadTimeout:  s.cfg.ReadTimeout,
+		WriteTimeout: s.cfg.WriteTimeout,
+		IdleTimeout:  s.cfg.IdleTimeout,
+	}
+}
@@ -0,0 +1,243 @@
+// Copyright 2022 Larry Rau. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsrv
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/larryr/lsrv/lsrv/store"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	fs, err := store.NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFSStore: %v", err)
+	}
+	srv, err := NewServer(Config{Store: fs})
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestHandlers(t *testing.T) {
+	ts := newTestServer(t)
+	client := ts.Client()
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"view of missing page redirects to edit", http.MethodGet, "/view/Missing", "", http.StatusOK, "Editing Missing"},
+		{"edit of new page", http.MethodGet, "/edit/NewPage", "", http.StatusOK, "Editing NewPage"},
+		{"save then view shows saved body", http.MethodPost, "/save/Hello", "body=hi+there", http.StatusOK, "hi there"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp *http.Response
+			var err error
+			switch tt.method {
+			case http.MethodGet:
+				resp, err = client.Get(ts.URL + tt.path)
+			case http.MethodPost:
+				resp, err = client.Post(ts.URL+tt.path, "application/x-www-form-urlencoded", strings.NewReader(tt.body))
+			}
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode == http.StatusFound {
+				loc := resp.Header.Get("Location")
+				resp, err = client.Get(ts.URL + loc)
+				if err != nil {
+					t.Fatalf("follow redirect to %s: %v", loc, err)
+				}
+				defer resp.Body.Close()
+			}
+
+			got, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if !strings.Contains(string(got), tt.wantBody) {
+				t.Errorf("body = %q, want substring %q", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestHistoryAndDiff(t *testing.T) {
+	ts := newTestServer(t)
+	client := ts.Client()
+
+	if _, err := client.Post(ts.URL+"/save/Page", "application/x-www-form-urlencoded", strings.NewReader("body=one")); err != nil {
+		t.Fatalf("save one: %v", err)
+	}
+	if _, err := client.Post(ts.URL+"/save/Page", "application/x-www-form-urlencoded", strings.NewReader("body=two")); err != nil {
+		t.Fatalf("save two: %v", err)
+	}
+
+	resp, err := client.Get(ts.URL + "/history/Page")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("history status = %d, want 200", resp.StatusCode)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), "History: Page") {
+		t.Errorf("history body = %q, missing title", body)
+	}
+	if !strings.Contains(string(body), "diff vs previous") {
+		t.Errorf("history body missing a diff link: %q", body)
+	}
+	if !strings.Contains(string(body), `action="/revert/Page/`) {
+		t.Errorf("history body missing a revert form: %q", body)
+	}
+}
+
+func TestDiffHandler(t *testing.T) {
+	ts := newTestServer(t)
+	client := ts.Client()
+
+	for _, body := range []string{"one", "two"} {
+		if _, err := client.Post(ts.URL+"/save/Page", "application/x-www-form-urlencoded", strings.NewReader("body="+body)); err != nil {
+			t.Fatalf("save %q: %v", body, err)
+		}
+	}
+
+	resp, err := client.Get(ts.URL + "/history/Page")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	start := strings.Index(string(body), `href="/diff/`)
+	if start == -1 {
+		t.Fatalf("no diff link in history page: %q", body)
+	}
+	quoteStart := start + len(`href="`)
+	quoteEnd := strings.Index(string(body)[quoteStart:], `"`)
+	link := string(body)[quoteStart : quoteStart+quoteEnd]
+
+	resp, err = client.Get(ts.URL + link)
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("diff status = %d, want 200", resp.StatusCode)
+	}
+	diffBody, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(diffBody), "one") || !strings.Contains(string(diffBody), "two") {
+		t.Errorf("diff body = %q, want both revisions represented", diffBody)
+	}
+}
+
+func TestRevertHandler(t *testing.T) {
+	ts := newTestServer(t)
+	client := ts.Client()
+
+	for _, body := range []string{"original", "overwritten"} {
+		if _, err := client.Post(ts.URL+"/save/Page", "application/x-www-form-urlencoded", strings.NewReader("body="+body)); err != nil {
+			t.Fatalf("save %q: %v", body, err)
+		}
+	}
+
+	resp, err := client.Get(ts.URL + "/history/Page")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	histBody, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	start := strings.LastIndex(string(histBody), `action="/revert/`)
+	if start == -1 {
+		t.Fatalf("no revert form in history page: %q", histBody)
+	}
+	quoteStart := start + len(`action="`)
+	quoteEnd := strings.Index(string(histBody)[quoteStart:], `"`)
+	revertPath := string(histBody)[quoteStart : quoteStart+quoteEnd]
+
+	resp, err = client.Post(ts.URL+revertPath, "application/x-www-form-urlencoded", nil)
+	if err != nil {
+		t.Fatalf("revert: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("revert (after following redirect) status = %d, want 200", resp.StatusCode)
+	}
+	got, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(got), "original") {
+		t.Errorf("after revert, view body = %q, want it to contain %q", got, "original")
+	}
+}
+
+// TestRevertRequiresAuth is a regression test: /revert/ must be gated by
+// -auth-mode the same way /save/ is, since it writes a new revision too.
+func TestRevertRequiresAuth(t *testing.T) {
+	fs, err := store.NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFSStore: %v", err)
+	}
+	if err := fs.Save(&Page{Title: "Page", Body: []byte("secret")}); err != nil {
+		t.Fatalf("seed Save: %v", err)
+	}
+	revs, err := fs.History("Page")
+	if err != nil || len(revs) == 0 {
+		t.Fatalf("History: %v (%d revs)", err, len(revs))
+	}
+
+	htpasswd := filepath.Join(t.TempDir(), "htpasswd")
+	if err := os.WriteFile(htpasswd, []byte("admin:$2a$10$.p7auat3elySk4.4dPhYi.b8mi8VGmL9HL7HKIzwCi778CX0HDIRG\n"), 0600); err != nil {
+		t.Fatalf("write htpasswd: %v", err)
+	}
+
+	srv, err := NewServer(Config{Store: fs, AuthMode: "edit", HtpasswdPath: htpasswd})
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, err := ts.Client().Post(ts.URL+"/revert/Page/"+revs[0].ID, "application/x-www-form-urlencoded", nil)
+	if err != nil {
+		t.Fatalf("revert without credentials: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("unauthenticated revert status = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+}
+
+// TestNewServerRejectsUnknownAuthMode is a regression test: a typo'd
+// -auth-mode value must fail startup rather than silently leaving
+// /edit/ and /save/ unauthenticated.
+func TestNewServerRejectsUnknownAuthMode(t *testing.T) {
+	fs, err := store.NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFSStore: %v", err)
+	}
+	if _, err := NewServer(Config{Store: fs, AuthMode: "al"}); err == nil {
+		t.Errorf("NewServer should reject an unknown AuthMode")
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2022 Larry Rau. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FSStore is a PageStore rooted at a directory on the local filesystem.
+// Each page gets its own subdirectory under "pages/" holding one
+// "{unix-nanos}.txt" file per revision, with a HEAD symlink pointing at
+// the current one.
+type FSStore struct {
+	dir string
+}
+
+// NewFSStore returns an FSStore rooted at dir, creating dir if it does
+// not already exist.
+func NewFSStore(dir string) (*FSStore, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "pages"), 0700); err != nil {
+		return nil, err
+	}
+	return &FSStore{dir: dir}, nil
+}
+
+func (s *FSStore) pagesDir() string {
+	return filepath.Join(s.dir, "pages")
+}
+
+func (s *FSStore) pageDir(title string) string {
+	return filepath.Join(s.pagesDir(), title)
+}
+
+func (s *FSStore) headPath(title string) string {
+	return filepath.Join(s.pageDir(title), "HEAD")
+}
+
+func (s *FSStore) revPath(title, revID string) string {
+	return filepath.Join(s.pageDir(title), revID+".txt")
+}
+
+func (s *FSStore) Load(title string) (*Page, error) {
+	body, err := os.ReadFile(s.headPath(title))
+	if err != nil {
+		return nil, err
+	}
+	return &Page{Title: title, Body: body}, nil
+}
+
+func (s *FSStore) Save(p *Page) error {
+	if err := os.MkdirAll(s.pageDir(p.Title), 0700); err != nil {
+		return err
+	}
+	revID := strconv.FormatInt(time.Now().UnixNano(), 10)
+	if err := os.WriteFile(s.revPath(p.Title, revID), p.Body, 0600); err != nil {
+		return err
+	}
+
+	// Point HEAD at the new revision via a temp symlink renamed into
+	// place, rather than remove-then-symlink, so concurrent Loads never
+	// observe a moment with no HEAD at all.
+	head := s.headPath(p.Title)
+	tmpHead := head + "." + revID + ".tmp"
+	if err := os.Symlink(revID+".txt", tmpHead); err != nil {
+		return err
+	}
+	return os.Rename(tmpHead, head)
+}
+
+func (s *FSStore) Delete(title string) error {
+	return os.RemoveAll(s.pageDir(title))
+}
+
+func (s *FSStore) List() ([]string, error) {
+	entries, err := os.ReadDir(s.pagesDir())
+	if err != nil {
+		return nil, err
+	}
+	var titles []string
+	for _, e := range entries {
+		if e.IsDir() {
+			titles = append(titles, e.Name())
+		}
+	}
+	sort.Strings(titles)
+	return titles, nil
+}
+
+func (s *FSStore) History(title string) ([]Revision, error) {
+	entries, err := os.ReadDir(s.pageDir(title))
+	if err != nil {
+		return nil, err
+	}
+	var revs []Revision
+	for _, e := range entries {
+		name := e.Name()
+		if name == "HEAD" || !strings.HasSuffix(name, ".txt") {
+			continue
+		}
+		id := strings.TrimSuffix(name, ".txt")
+		nanos, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			continue
+		}
+		revs = append(revs, Revision{ID: id, Title: title, Timestamp: time.Unix(0, nanos)})
+	}
+	sort.Slice(revs, func(i, j int) bool { return revs[i].Timestamp.After(revs[j].Timestamp) })
+	return revs, nil
+}
+
+func (s *FSStore) LoadRevision(title, revID string) (*Page, error) {
+	body, err := os.ReadFile(s.revPath(title, revID))
+	if err != nil {
+		return nil, err
+	}
+	return &Page{Title: title, Body: body}, nil
+}
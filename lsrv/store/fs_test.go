@@ -0,0 +1,63 @@
+// Copyright 2022 Larry Rau. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package store
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestFSStoreSaveLoad(t *testing.T) {
+	s, err := NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFSStore: %v", err)
+	}
+	testPageStoreSaveLoad(t, s)
+}
+
+func TestFSStoreHistoryAndLoadRevision(t *testing.T) {
+	s, err := NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFSStore: %v", err)
+	}
+	testPageStoreHistoryAndLoadRevision(t, s)
+}
+
+// TestFSStoreConcurrentSave exercises the HEAD-pointer swap in Save under
+// concurrent writers: every Save must succeed (with a remove-then-symlink
+// update, one writer's Symlink can fail on a HEAD another writer just
+// created) and HEAD must still resolve afterwards. No Load runs while the
+// Saves are in flight.
+func TestFSStoreConcurrentSave(t *testing.T) {
+	s, err := NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFSStore: %v", err)
+	}
+	if err := s.Save(&Page{Title: "Race", Body: []byte("seed")}); err != nil {
+		t.Fatalf("seed Save: %v", err)
+	}
+
+	const n = 30
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- s.Save(&Page{Title: "Race", Body: []byte("body")})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("concurrent Save: %v", err)
+		}
+	}
+
+	if _, err := s.Load("Race"); err != nil {
+		t.Errorf("Load after concurrent Save: %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2022 Larry Rau. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqliteTimestampFormat matches SQLite's CURRENT_TIMESTAMP default layout.
+const sqliteTimestampFormat = "2006-01-02 15:04:05"
+
+// SQLiteStore is a PageStore backed by a SQLite database, using the
+// cgo-free modernc.org/sqlite driver so lsrv stays a static binary.
+// Every save appends a row to revisions rather than overwriting one.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens (creating if necessary) the SQLite database at
+// path and ensures the revisions table exists.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, err
+	}
+	// database/sql pools connections, but SQLite only allows one writer
+	// at a time; concurrent Save calls on separate connections hit
+	// SQLITE_BUSY even with a busy_timeout set. Pin the pool to a
+	// single connection so writes simply queue instead of failing.
+	db.SetMaxOpenConns(1)
+
+	const schema = `
+CREATE TABLE IF NOT EXISTS revisions (
+	title  TEXT NOT NULL,
+	rev_id INTEGER NOT NULL,
+	body   BLOB NOT NULL,
+	author TEXT NOT NULL DEFAULT '',
+	ts     TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (title, rev_id)
+)`
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+func (s *SQLiteStore) Load(title string) (*Page, error) {
+	var body []byte
+	err := s.db.QueryRow(
+		`SELECT body FROM revisions WHERE title = ? ORDER BY rev_id DESC LIMIT 1`, title,
+	).Scan(&body)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, os.ErrNotExist
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Page{Title: title, Body: body}, nil
+}
+
+func (s *SQLiteStore) Save(p *Page) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var maxID sql.NullInt64
+	if err := tx.QueryRow(`SELECT MAX(rev_id) FROM revisions WHERE title = ?`, p.Title).Scan(&maxID); err != nil {
+		return err
+	}
+	nextID := int64(1)
+	if maxID.Valid {
+		nextID = maxID.Int64 + 1
+	}
+	if _, err := tx.Exec(
+		`INSERT INTO revisions (title, rev_id, body) VALUES (?, ?, ?)`, p.Title, nextID, p.Body,
+	); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLiteStore) Delete(title string) error {
+	_, err := s.db.Exec(`DELETE FROM revisions WHERE title = ?`, title)
+	return err
+}
+
+func (s *SQLiteStore) List() ([]string, error) {
+	rows, err := s.db.Query(`SELECT DISTINCT title FROM revisions ORDER BY title`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var titles []string
+	for rows.Next() {
+		var title string
+		if err := rows.Scan(&title); err != nil {
+			return nil, err
+		}
+		titles = append(titles, title)
+	}
+	return titles, rows.Err()
+}
+
+func (s *SQLiteStore) History(title string) ([]Revision, error) {
+	rows, err := s.db.Query(
+		`SELECT rev_id, author, ts FROM revisions WHERE title = ? ORDER BY rev_id DESC`, title,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revs []Revision
+	for rows.Next() {
+		var revID int64
+		var author string
+		var ts string
+		if err := rows.Scan(&revID, &author, &ts); err != nil {
+			return nil, err
+		}
+		parsedTS, _ := time.Parse(sqliteTimestampFormat, ts)
+		revs = append(revs, Revision{
+			ID:        strconv.FormatInt(revID, 10),
+			Title:     title,
+			Author:    author,
+			Timestamp: parsedTS,
+		})
+	}
+	return revs, rows.Err()
+}
+
+func (s *SQLiteStore) LoadRevision(title, revID string) (*Page, error) {
+	var body []byte
+	err := s.db.QueryRow(
+		`SELECT body FROM revisions WHERE title = ? AND rev_id = ?`, title, revID,
+	).Scan(&body)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, os.ErrNotExist
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Page{Title: title, Body: body}, nil
+}
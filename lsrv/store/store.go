@@ -0,0 +1,43 @@
+// Copyright 2022 Larry Rau. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package store defines the persistence layer for lsrv wiki pages and
+// provides filesystem and SQLite implementations, so the wiki can be
+// deployed as more than one instance against shared storage.
+package store
+
+import "time"
+
+// Page is a single wiki page as held by a PageStore.
+type Page struct {
+	Title string
+	Body  []byte
+}
+
+// Revision is one historical save of a page. Body is intentionally
+// omitted from listings (see PageStore.History); fetch it with
+// LoadRevision when a specific revision's content is needed.
+type Revision struct {
+	ID        string
+	Title     string
+	Author    string
+	Timestamp time.Time
+}
+
+// PageStore persists and retrieves wiki pages. Save appends a new
+// revision rather than overwriting, so pages keep a full history.
+// Implementations must be safe for concurrent use.
+type PageStore interface {
+	// Load returns the current (most recent) revision of title.
+	Load(title string) (*Page, error)
+	// Save appends a new revision of p, making it the current one.
+	Save(p *Page) error
+	List() ([]string, error)
+	Delete(title string) error
+
+	// History returns title's revisions, most recent first.
+	History(title string) ([]Revision, error)
+	// LoadRevision returns title's content as of a specific revision.
+	LoadRevision(title, revID string) (*Page, error)
+}
@@ -0,0 +1,90 @@
+// Copyright 2022 Larry Rau. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package store
+
+import "testing"
+
+// testPageStoreSaveLoad exercises the basic Save/Load/List/Delete
+// contract common to every PageStore implementation.
+func testPageStoreSaveLoad(t *testing.T, s PageStore) {
+	t.Helper()
+
+	if _, err := s.Load("Missing"); err == nil {
+		t.Errorf("Load of unsaved page should error")
+	}
+
+	if err := s.Save(&Page{Title: "Home", Body: []byte("hello")}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	p, err := s.Load("Home")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if string(p.Body) != "hello" {
+		t.Errorf("Load body = %q, want %q", p.Body, "hello")
+	}
+
+	if err := s.Save(&Page{Title: "Home", Body: []byte("world")}); err != nil {
+		t.Fatalf("2nd Save: %v", err)
+	}
+	p, err = s.Load("Home")
+	if err != nil {
+		t.Fatalf("Load after 2nd Save: %v", err)
+	}
+	if string(p.Body) != "world" {
+		t.Errorf("Load after 2nd Save body = %q, want %q", p.Body, "world")
+	}
+
+	titles, err := s.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(titles) != 1 || titles[0] != "Home" {
+		t.Errorf("List = %v, want [Home]", titles)
+	}
+
+	if err := s.Delete("Home"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Load("Home"); err == nil {
+		t.Errorf("Load after Delete should error")
+	}
+}
+
+// testPageStoreHistoryAndLoadRevision checks that History returns
+// revisions most-recent-first and that each one is independently
+// retrievable via LoadRevision.
+func testPageStoreHistoryAndLoadRevision(t *testing.T, s PageStore) {
+	t.Helper()
+
+	for _, body := range []string{"one", "two", "three"} {
+		if err := s.Save(&Page{Title: "Page", Body: []byte(body)}); err != nil {
+			t.Fatalf("Save %q: %v", body, err)
+		}
+	}
+
+	revs, err := s.History("Page")
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if len(revs) != 3 {
+		t.Fatalf("History returned %d revisions, want 3", len(revs))
+	}
+
+	want := []string{"three", "two", "one"}
+	for i, rev := range revs {
+		p, err := s.LoadRevision("Page", rev.ID)
+		if err != nil {
+			t.Fatalf("LoadRevision(%s): %v", rev.ID, err)
+		}
+		if string(p.Body) != want[i] {
+			t.Errorf("revision %d body = %q, want %q", i, p.Body, want[i])
+		}
+	}
+
+	if _, err := s.LoadRevision("Page", "does-not-exist"); err == nil {
+		t.Errorf("LoadRevision of an unknown revision should error")
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2022 Larry Rau. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsrv
+
+import "html/template"
+
+const editHTML = `
+<h1>Editing {{.Title}}</h1>
+
+<form action="/save/{{.Title}}" method="POST">
+<div><textarea name="body" rows="20" cols="80">{{printf "%s" .Body}}</textarea></div>
+<div><input type="submit" value="Save"></div>
+</form>`
+
+const viewHTML = `
+<h1>{{.Title}}</h1>
+<p>[<a href="/edit/{{.Title}}">edit</a>] [<a href="/history/{{.Title}}">history</a>]</p>
+<div>{{.Body}}</div>
+`
+
+const historyHTML = `
+<h1>History: {{.Title}}</h1>
+<ul>
+{{range .Revisions}}<li>
+{{.ID}} {{.Timestamp}}
+[<a href="/view/{{$.Title}}">view</a>]
+{{if .PrevID}}[<a href="/diff/{{$.Title}}/{{.PrevID}}/{{.ID}}">diff vs previous</a>]{{end}}
+<form method="POST" action="/revert/{{$.Title}}/{{.ID}}" style="display:inline">
+<input type="submit" value="revert to this revision">
+</form>
+</li>
+{{end}}</ul>
+`
+
+const diffHTML = `
+<h1>Diff: {{.Title}} {{.RevA}}..{{.RevB}}</h1>
+<pre>{{.Diff}}</pre>
+`
+
+// parseTemplates builds the Server's template set from the embedded
+// constants above, so a Server is self-contained and doesn't depend on
+// template files existing on disk.
+func parseTemplates() (*template.Template, error) {
+	t := template.New("lsrv")
+	for _, tpl := range []struct{ name, body string }{
+		{"edit.html", editHTML},
+		{"view.html", viewHTML},
+		{"history.html", historyHTML},
+		{"diff.html", diffHTML},
+	} {
+		if _, err := t.New(tpl.name).Parse(tpl.body); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}